@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"dagger.io/dagger"
 )
@@ -20,7 +23,7 @@ func main() {
 
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: go run main.go <command>")
-		fmt.Println("Commands: test-local, release")
+		fmt.Println("Commands: test-local, release, publish, dev")
 		os.Exit(1)
 	}
 
@@ -28,15 +31,45 @@ func main() {
 
 	switch command {
 	case "test-local":
-		if err := testLocal(ctx, client); err != nil {
+		opts, err := parseTestLocalFlags(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing test-local flags: %v\n", err)
+			os.Exit(1)
+		}
+		if err := testLocal(ctx, client, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running test-local: %v\n", err)
 			os.Exit(1)
 		}
 	case "release":
-		if err := release(ctx, client); err != nil {
+		opts, err := parseReleaseFlags(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing release flags: %v\n", err)
+			os.Exit(1)
+		}
+		if err := release(ctx, client, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running release: %v\n", err)
 			os.Exit(1)
 		}
+	case "publish":
+		opts, err := parsePublishFlags(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing publish flags: %v\n", err)
+			os.Exit(1)
+		}
+		if err := publish(ctx, client, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running publish: %v\n", err)
+			os.Exit(1)
+		}
+	case "dev", "shell":
+		opts, err := parseDevFlags(os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing dev flags: %v\n", err)
+			os.Exit(1)
+		}
+		if err := dev(ctx, client, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running dev: %v\n", err)
+			os.Exit(1)
+		}
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		os.Exit(1)
@@ -72,16 +105,169 @@ func getProjectSourceDirectory(client *dagger.Client) (*dagger.Directory, error)
 	return sourceDir, nil
 }
 
+// sccacheVersion pins the sccache release downloaded by withToolchainCaches.
+const sccacheVersion = "0.8.2"
+
+// withToolchainCaches mounts the cargo registry/git and target caches, plus
+// sccache's own cache volume, and wires RUSTC_WRAPPER so repeated runs reuse
+// previously compiled artifacts instead of starting from a cold cache.
+//
+// cacheKey scopes the target directory cache to the caller (e.g. a target
+// triple, or a stage name like "coverage"/"msrv"). cargo serializes
+// concurrent access to a shared target dir via its own lock, so stages
+// meant to run in parallel — or against a different toolchain, like
+// msrvStage — need their own target cache rather than contending for (and
+// invalidating) one shared across every stage.
+func withToolchainCaches(client *dagger.Client, container *dagger.Container, cacheKey string) *dagger.Container {
+	cargoRegistry := client.CacheVolume("zephyrite-cargo-registry")
+	cargoGit := client.CacheVolume("zephyrite-cargo-git")
+	targetCache := client.CacheVolume("zephyrite-target-" + cacheKey)
+	sccacheCache := client.CacheVolume("zephyrite-sccache")
+
+	sccacheDist := fmt.Sprintf("sccache-v%s-x86_64-unknown-linux-musl", sccacheVersion)
+	sccacheURL := fmt.Sprintf("https://github.com/mozilla/sccache/releases/download/v%s/%s.tar.gz", sccacheVersion, sccacheDist)
+
+	return container.
+		WithMountedCache("/usr/local/cargo/registry", cargoRegistry).
+		WithMountedCache("/usr/local/cargo/git", cargoGit).
+		WithMountedCache("/workspace/target", targetCache).
+		WithMountedCache("/root/.cache/sccache", sccacheCache).
+		WithExec([]string{"sh", "-c", fmt.Sprintf("curl -L %s | tar xz -C /tmp", sccacheURL)}).
+		WithExec([]string{"sh", "-c", fmt.Sprintf("install -m 755 /tmp/%s/sccache /usr/local/bin/sccache", sccacheDist)}).
+		WithEnvVariable("RUSTC_WRAPPER", "/usr/local/bin/sccache").
+		WithEnvVariable("SCCACHE_DIR", "/root/.cache/sccache")
+}
+
+// testLocalOptions gates the optional, slower stages of test-local behind
+// flags so quick local iteration stays fast by default.
+type testLocalOptions struct {
+	withCoverage  bool
+	withAudit     bool
+	withMSRV      bool
+	auditSeverity string
+}
+
+// parseTestLocalFlags parses the flags accepted by the test-local subcommand.
+func parseTestLocalFlags(args []string) (testLocalOptions, error) {
+	fs := flag.NewFlagSet("test-local", flag.ContinueOnError)
+	withCoverage := fs.Bool("with-coverage", false, "run cargo-llvm-cov and export an lcov + HTML report to ./coverage/")
+	withAudit := fs.Bool("with-audit", false, "run cargo audit and cargo deny check against RustSec/advisory-db")
+	withMSRV := fs.Bool("with-msrv", false, "re-run cargo check --all-features on the toolchain pinned by Cargo.toml's rust-version")
+	auditSeverity := fs.String("audit-severity", "medium", "minimum advisory severity that fails cargo-deny's half of --with-audit (cargo audit itself has no severity gate and fails on any advisory)")
+
+	if err := fs.Parse(args); err != nil {
+		return testLocalOptions{}, err
+	}
+
+	return testLocalOptions{
+		withCoverage:  *withCoverage,
+		withAudit:     *withAudit,
+		withMSRV:      *withMSRV,
+		auditSeverity: *auditSeverity,
+	}, nil
+}
+
+// coverageStage runs cargo-llvm-cov and exports an lcov report plus an HTML
+// report to ./coverage/.
+func coverageStage(ctx context.Context, client *dagger.Client, sourceDir *dagger.Directory) error {
+	pipeline := client.Pipeline("coverage")
+
+	container := pipeline.Container().
+		From("rust:1.85").
+		WithWorkdir("/workspace").
+		WithDirectory("/workspace", sourceDir).
+		WithExec([]string{"rustup", "component", "add", "llvm-tools-preview"}).
+		WithExec([]string{"cargo", "install", "cargo-llvm-cov", "--locked"})
+
+	container = withToolchainCaches(pipeline, container, "coverage")
+	container = container.WithExec([]string{"cargo", "llvm-cov", "--all-features", "--workspace", "--lcov", "--output-path", "lcov.info"})
+	container = container.WithExec([]string{"cargo", "llvm-cov", "--all-features", "--workspace", "--html"})
+	// /workspace/target is a cache volume, whose contents can't be read back
+	// out of the container directly, so copy the report somewhere that can.
+	container = container.WithExec([]string{"cp", "-r", "/workspace/target/llvm-cov/html", "/workspace/coverage-html"})
+
+	if _, err := container.File("/workspace/lcov.info").Export(ctx, "./coverage/lcov.info"); err != nil {
+		return fmt.Errorf("failed to export lcov.info: %w", err)
+	}
+	if _, err := container.Directory("/workspace/coverage-html").Export(ctx, "./coverage/html"); err != nil {
+		return fmt.Errorf("failed to export coverage HTML report: %w", err)
+	}
+
+	return nil
+}
+
+// auditStage runs cargo audit and cargo deny check against RustSec's
+// advisory database. cargo audit has no severity gate of its own and always
+// fails on any advisory; severity only filters cargo-deny's check, via
+// deny.toml's severity-threshold.
+func auditStage(ctx context.Context, client *dagger.Client, sourceDir *dagger.Directory, severity string) error {
+	pipeline := client.Pipeline("audit")
+
+	container := pipeline.Container().
+		From("rust:1.85").
+		WithWorkdir("/workspace").
+		WithDirectory("/workspace", sourceDir).
+		WithExec([]string{"cargo", "install", "cargo-audit", "--locked"}).
+		WithExec([]string{"cargo", "install", "cargo-deny", "--locked"})
+
+	container = withToolchainCaches(pipeline, container, "audit")
+	container = container.WithExec([]string{"cargo", "audit", "--deny", "warnings"})
+	container = container.WithExec([]string{"sh", "-c", fmt.Sprintf(`if [ -f deny.toml ]; then
+  grep -q '^severity-threshold' deny.toml || { echo "deny.toml exists but has no top-level severity-threshold key; --audit-severity=%s would have no effect" >&2; exit 1; }
+  sed -i 's/^severity-threshold.*/severity-threshold = "%s"/' deny.toml
+fi`, severity, severity)})
+	container = container.WithExec([]string{"cargo", "deny", "check"})
+
+	_, err := container.Stdout(ctx)
+	return err
+}
+
+// msrvStage reads Cargo.toml's rust-version and re-runs cargo check on that
+// exact toolchain, catching accidental use of newer language features.
+func msrvStage(ctx context.Context, client *dagger.Client, sourceDir *dagger.Directory) error {
+	cargoToml, err := sourceDir.File("Cargo.toml").Contents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read Cargo.toml: %w", err)
+	}
+
+	msrv := ""
+	for _, line := range strings.Split(cargoToml, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "rust-version") {
+			msrv = strings.Trim(strings.TrimSpace(strings.SplitN(line, "=", 2)[1]), `"`)
+			break
+		}
+	}
+	if msrv == "" {
+		return fmt.Errorf("could not find rust-version in Cargo.toml")
+	}
+
+	pipeline := client.Pipeline("msrv")
+
+	container := pipeline.Container().
+		From(fmt.Sprintf("rust:%s", msrv)).
+		WithWorkdir("/workspace").
+		WithDirectory("/workspace", sourceDir)
+
+	container = withToolchainCaches(pipeline, container, "msrv-"+msrv)
+	container = container.WithExec([]string{"cargo", "check", "--all-features"})
+
+	_, err = container.Stdout(ctx)
+	return err
+}
+
 // testLocal mirrors the CI pipeline locally
-func testLocal(ctx context.Context, client *dagger.Client) error {
+func testLocal(ctx context.Context, client *dagger.Client, opts testLocalOptions) error {
 	fmt.Println("🧪 Running Zephyrite test suite locally (mirroring CI)")
 
+	client = client.Pipeline("Zephyrite CI")
+
 	sourceDir, err := getProjectSourceDirectory(client)
 	if err != nil {
 		return fmt.Errorf("failed to get project source directory: %w", err)
 	}
 
-	container := client.Container().
+	base := client.Container().
 		From("rust:1.85").
 		WithWorkdir("/workspace").
 		WithDirectory("/workspace", sourceDir).
@@ -89,35 +275,504 @@ func testLocal(ctx context.Context, client *dagger.Client) error {
 		WithExec([]string{"apt-get", "install", "-y", "curl"}).
 		WithExec([]string{"rustup", "component", "add", "rustfmt", "clippy"})
 
-	container = container.WithExec([]string{"cargo", "install", "cargo-nextest", "--locked"})
+	base = withToolchainCaches(client, base, "test")
+	base = base.WithExec([]string{"cargo", "install", "cargo-nextest", "--locked"})
 
 	fmt.Println("📋 Checking formatting...")
-	container = container.WithExec([]string{"cargo", "fmt", "--all", "--", "--check"})
+	fmtStage := base.Pipeline("fmt").WithExec([]string{"cargo", "fmt", "--all", "--", "--check"})
 
 	fmt.Println("🔍 Running clippy...")
-	container = container.WithExec([]string{"cargo", "clippy", "--all-targets", "--all-features", "--", "-D", "warnings"})
+	clippyStage := fmtStage.Pipeline("clippy").WithExec([]string{"cargo", "clippy", "--all-targets", "--all-features", "--", "-D", "warnings"})
 
 	fmt.Println("🔨 Building...")
-	container = container.WithExec([]string{"cargo", "build", "--verbose"})
+	buildStage := clippyStage.Pipeline("build").WithExec([]string{"cargo", "build", "--verbose"})
 
 	fmt.Println("🧪 Running tests with nextest...")
-	container = container.WithExec([]string{"cargo", "nextest", "run", "--config-file", ".cargo/nextest.toml", "--profile", "ci"})
+	nextestStage := buildStage.Pipeline("nextest").WithExec([]string{"cargo", "nextest", "run", "--config-file", ".cargo/nextest.toml", "--profile", "ci"})
 
 	fmt.Println("📚 Running doctests...")
-	container = container.WithExec([]string{"cargo", "test", "--doc", "--verbose"})
+	doctestStage := nextestStage.Pipeline("doctest").WithExec([]string{"cargo", "test", "--doc", "--verbose"})
 
-	_, err = container.Stdout(ctx)
-	if err != nil {
-		return fmt.Errorf("test pipeline failed: %w", err)
+	container := doctestStage.WithExec([]string{"sccache", "--show-stats"})
+
+	type namedStage struct {
+		name string
+		run  func() error
+	}
+
+	stages := []namedStage{
+		{"fmt/clippy/build/nextest/doctest", func() error {
+			out, err := container.Stdout(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			return nil
+		}},
+	}
+	if opts.withCoverage {
+		stages = append(stages, namedStage{"coverage", func() error { return coverageStage(ctx, client, sourceDir) }})
+	}
+	if opts.withAudit {
+		stages = append(stages, namedStage{"audit", func() error { return auditStage(ctx, client, sourceDir, opts.auditSeverity) }})
+	}
+	if opts.withMSRV {
+		stages = append(stages, namedStage{"msrv", func() error { return msrvStage(ctx, client, sourceDir) }})
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+
+	for _, stage := range stages {
+		stage := stage
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stage.run(); err != nil {
+				mu.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", stage.name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d stage(s) failed:\n%s", len(failed), strings.Join(failed, "\n"))
 	}
 
 	fmt.Println("✅ All tests passed locally!")
 	return nil
 }
 
-// release handles building release artifacts
-func release(ctx context.Context, client *dagger.Client) error {
-	fmt.Println("🚀 Building Zephyrite release artifacts")
+// releaseOptions configures the release subcommand's target matrix.
+type releaseOptions struct {
+	targets []string
+	version string
+	gpgKey  string
+}
+
+// defaultReleaseTargets is the target matrix built when --targets is omitted.
+var defaultReleaseTargets = []string{
+	"x86_64-unknown-linux-gnu",
+	"x86_64-unknown-linux-musl",
+	"aarch64-unknown-linux-gnu",
+	"aarch64-unknown-linux-musl",
+	"x86_64-apple-darwin",
+	"aarch64-apple-darwin",
+	"x86_64-pc-windows-gnu",
+}
+
+// parseReleaseFlags parses the flags accepted by the release subcommand.
+func parseReleaseFlags(args []string) (releaseOptions, error) {
+	fs := flag.NewFlagSet("release", flag.ContinueOnError)
+	targets := fs.String("targets", strings.Join(defaultReleaseTargets, ","), "comma-separated list of Rust target triples to build")
+	version := fs.String("version", "dev", "version string embedded in archive names")
+	gpgKey := fs.String("gpg-key", "", "path to an armored GPG private key used to sign SHA256SUMS")
+
+	if err := fs.Parse(args); err != nil {
+		return releaseOptions{}, err
+	}
+
+	return releaseOptions{
+		targets: strings.Split(*targets, ","),
+		version: *version,
+		gpgKey:  *gpgKey,
+	}, nil
+}
+
+// archiveName returns the dist archive name and extension for a given target.
+func archiveName(target, version string) (name, ext string) {
+	if strings.Contains(target, "windows") {
+		return fmt.Sprintf("zephyrite-%s-%s.zip", version, target), "zip"
+	}
+	return fmt.Sprintf("zephyrite-%s-%s.tar.gz", version, target), "tar.gz"
+}
+
+// linuxCrossPackages maps a non-native Linux target to the apt package and
+// linker cargo needs to cross-compile for it without a container engine
+// (`cross` requires Docker-in-Docker, which this pipeline doesn't have).
+var linuxCrossPackages = map[string]struct {
+	aptPackage string
+	linker     string
+}{
+	"aarch64-unknown-linux-gnu":  {"gcc-aarch64-linux-gnu", "aarch64-linux-gnu-gcc"},
+	"aarch64-unknown-linux-musl": {"gcc-aarch64-linux-gnu", "aarch64-linux-gnu-gcc"},
+}
+
+// cargoLinkerEnvVar returns the CARGO_TARGET_<TRIPLE>_LINKER env var name
+// cargo reads to pick a target-specific linker.
+func cargoLinkerEnvVar(target string) string {
+	return "CARGO_TARGET_" + strings.ToUpper(strings.ReplaceAll(target, "-", "_")) + "_LINKER"
+}
+
+// linuxContainer builds a native or cross-compiling container for a Linux
+// target using rustup + the matching apt cross toolchain, no `cross`/Docker
+// required.
+func linuxContainer(pipeline *dagger.Client, sourceDir *dagger.Directory, target string) *dagger.Container {
+	container := pipeline.Container().
+		From("rust:1.85").
+		WithWorkdir("/workspace").
+		WithDirectory("/workspace", sourceDir).
+		WithExec([]string{"rustup", "target", "add", target})
+
+	if strings.Contains(target, "musl") {
+		container = container.
+			WithExec([]string{"apt-get", "update"}).
+			WithExec([]string{"apt-get", "install", "-y", "musl-tools"})
+	}
+
+	if pkg, ok := linuxCrossPackages[target]; ok {
+		container = container.
+			WithExec([]string{"apt-get", "update"}).
+			WithExec([]string{"apt-get", "install", "-y", pkg.aptPackage}).
+			WithEnvVariable(cargoLinkerEnvVar(target), pkg.linker)
+	}
+
+	return withToolchainCaches(pipeline, container, target)
+}
+
+// windowsContainer builds an x86_64-pc-windows-gnu container using the
+// mingw-w64 cross toolchain, which needs no container engine either.
+func windowsContainer(pipeline *dagger.Client, sourceDir *dagger.Directory, target string) *dagger.Container {
+	container := pipeline.Container().
+		From("rust:1.85").
+		WithWorkdir("/workspace").
+		WithDirectory("/workspace", sourceDir).
+		WithExec([]string{"apt-get", "update"}).
+		WithExec([]string{"apt-get", "install", "-y", "gcc-mingw-w64-x86-64", "zip"}).
+		WithExec([]string{"rustup", "target", "add", target}).
+		WithEnvVariable(cargoLinkerEnvVar(target), "x86_64-w64-mingw32-gcc")
+
+	return withToolchainCaches(pipeline, container, target)
+}
+
+// darwinContainer builds an Apple target using a prebuilt osxcross image:
+// `cross` doesn't support Apple targets at all (it can't ship a licensed
+// macOS SDK), and Docker-in-Docker isn't wired up here regardless.
+func darwinContainer(pipeline *dagger.Client, sourceDir *dagger.Directory, target string) *dagger.Container {
+	container := pipeline.Container().
+		From("joseluisq/rust-linux-darwin-builder:1.85.0").
+		WithWorkdir("/workspace").
+		WithDirectory("/workspace", sourceDir).
+		WithExec([]string{"rustup", "target", "add", target})
+
+	return withToolchainCaches(pipeline, container, target)
+}
+
+// buildTarget cross-compiles the release binary for a single target,
+// packages it into a dist archive, and returns that archive's
+// `sha256sum`-formatted checksum line.
+func buildTarget(ctx context.Context, client *dagger.Client, sourceDir *dagger.Directory, target, version string) (string, error) {
+	pipeline := client.Pipeline(target)
+
+	var container *dagger.Container
+	switch {
+	case strings.HasSuffix(target, "-apple-darwin"):
+		container = darwinContainer(pipeline, sourceDir, target)
+	case strings.Contains(target, "windows"):
+		container = windowsContainer(pipeline, sourceDir, target)
+	default:
+		container = linuxContainer(pipeline, sourceDir, target)
+	}
+
+	container = container.WithExec([]string{"cargo", "build", "--release", "--target", target})
+
+	archive, ext := archiveName(target, version)
+	binaryPath := fmt.Sprintf("/workspace/target/%s/release/zephyrite", target)
+	if strings.Contains(target, "windows") {
+		binaryPath += ".exe"
+	}
+
+	packaged := container.WithWorkdir("/dist")
+	if ext == "zip" {
+		packaged = packaged.WithExec([]string{"sh", "-c", fmt.Sprintf("cp %s zephyrite.exe && zip %s zephyrite.exe", binaryPath, archive)})
+	} else {
+		packaged = packaged.WithExec([]string{"sh", "-c", fmt.Sprintf("cp %s zephyrite && tar czf %s zephyrite", binaryPath, archive)})
+	}
+
+	if _, err := packaged.File("/dist/" + archive).Export(ctx, "./dist/"+archive); err != nil {
+		return "", fmt.Errorf("failed to export archive for %s: %w", target, err)
+	}
+
+	checksum, err := packaged.WithExec([]string{"sh", "-c", fmt.Sprintf("sha256sum %s", archive)}).Stdout(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to checksum archive for %s: %w", target, err)
+	}
+
+	return strings.TrimSpace(checksum), nil
+}
+
+// signChecksums signs the combined SHA256SUMS file with the given GPG key,
+// producing a detached SHA256SUMS.asc armored signature.
+func signChecksums(ctx context.Context, client *dagger.Client, gpgKey string) error {
+	keyDir := client.Host().Directory(filepath.Dir(gpgKey))
+	distDir := client.Host().Directory("./dist")
+
+	container := client.Container().
+		From("alpine:3.19").
+		WithExec([]string{"apk", "add", "--no-cache", "gnupg"}).
+		WithDirectory("/keys", keyDir).
+		WithDirectory("/dist", distDir).
+		WithExec([]string{"sh", "-c", fmt.Sprintf("gpg --batch --import /keys/%s", filepath.Base(gpgKey))}).
+		WithExec([]string{"sh", "-c", "cd /dist && gpg --batch --yes --armor --detach-sign -o SHA256SUMS.asc SHA256SUMS"})
+
+	_, err := container.File("/dist/SHA256SUMS.asc").Export(ctx, "./dist/SHA256SUMS.asc")
+	return err
+}
+
+// release cross-compiles zephyrite for every target in the matrix in
+// parallel, packages each into a checksummed archive, and writes a combined
+// SHA256SUMS (optionally GPG-signed) under ./dist/.
+func release(ctx context.Context, client *dagger.Client, opts releaseOptions) error {
+	fmt.Printf("🚀 Building Zephyrite release artifacts for %d target(s) (version %s)\n", len(opts.targets), opts.version)
+
+	client = client.Pipeline("Zephyrite Release")
+
+	sourceDir, err := getProjectSourceDirectory(client)
+	if err != nil {
+		return fmt.Errorf("failed to get project source directory: %w", err)
+	}
+
+	// Remove any archives/SHA256SUMS left over from a previous run so stale
+	// artifacts from a different version or target matrix never linger
+	// alongside (and out of sync with) this run's output.
+	if err := os.RemoveAll("./dist"); err != nil {
+		return fmt.Errorf("failed to clean dist directory: %w", err)
+	}
+	if err := os.MkdirAll("./dist", 0o755); err != nil {
+		return fmt.Errorf("failed to create dist directory: %w", err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		checksums []string
+		buildErrs []error
+	)
+
+	for _, target := range opts.targets {
+		target := target
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			fmt.Printf("🔨 Building %s...\n", target)
+			checksum, err := buildTarget(ctx, client, sourceDir, target, opts.version)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				buildErrs = append(buildErrs, err)
+				return
+			}
+			checksums = append(checksums, checksum)
+		}()
+	}
+
+	wg.Wait()
+
+	if len(buildErrs) > 0 {
+		return fmt.Errorf("%d target(s) failed to build: %v", len(buildErrs), buildErrs)
+	}
+
+	sumsPath := filepath.Join("./dist", "SHA256SUMS")
+	if err := os.WriteFile(sumsPath, []byte(strings.Join(checksums, "\n")+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write SHA256SUMS: %w", err)
+	}
+
+	if opts.gpgKey != "" {
+		fmt.Println("✍️  Signing SHA256SUMS...")
+		if err := signChecksums(ctx, client, opts.gpgKey); err != nil {
+			return fmt.Errorf("failed to sign SHA256SUMS: %w", err)
+		}
+	}
+
+	fmt.Println("✅ Release artifacts built successfully!")
+	fmt.Println("📦 Archives available under: ./dist/")
+	return nil
+}
+
+// publishLinuxTargets maps the Rust targets we can containerize to their
+// corresponding OCI platform.
+var publishLinuxTargets = map[string]dagger.Platform{
+	"x86_64-unknown-linux-musl":  "linux/amd64",
+	"aarch64-unknown-linux-musl": "linux/arm64",
+}
+
+// publishOptions configures the publish subcommand.
+type publishOptions struct {
+	registry string
+	tag      string
+	dryRun   bool
+}
+
+// parsePublishFlags parses the flags accepted by the publish subcommand.
+func parsePublishFlags(args []string) (publishOptions, error) {
+	fs := flag.NewFlagSet("publish", flag.ContinueOnError)
+	registry := fs.String("registry", "ghcr.io/minikin/zephyrite", "OCI registry/repository to push the multi-arch image to")
+	tag := fs.String("tag", "dev", "tag to publish the image and GitHub Release under")
+	dryRun := fs.Bool("dry-run", false, "build the per-platform images without tagging, pushing or uploading anything")
+
+	if err := fs.Parse(args); err != nil {
+		return publishOptions{}, err
+	}
+
+	return publishOptions{
+		registry: *registry,
+		tag:      *tag,
+		dryRun:   *dryRun,
+	}, nil
+}
+
+// buildPlatformImage cross-compiles zephyrite for the given target using the
+// same rustup + apt cross-toolchain approach as the release matrix (no
+// `cross`/Docker-in-Docker, which this pipeline doesn't have), and layers the
+// resulting binary onto a distroless base for that platform.
+func buildPlatformImage(client *dagger.Client, sourceDir *dagger.Directory, target string, platform dagger.Platform) *dagger.Container {
+	pipeline := client.Pipeline(target)
+
+	builder := linuxContainer(pipeline, sourceDir, target)
+	builder = builder.WithExec([]string{"cargo", "build", "--release", "--target", target})
+	// The target directory is a cache volume, so copy the binary out of it
+	// before reading it back as a File.
+	builder = builder.WithExec([]string{"cp", fmt.Sprintf("/workspace/target/%s/release/zephyrite", target), "/zephyrite"})
+
+	binary := builder.File("/zephyrite")
+
+	return pipeline.Container(dagger.ContainerOpts{Platform: platform}).
+		From("gcr.io/distroless/cc-debian12").
+		WithFile("/usr/local/bin/zephyrite", binary).
+		WithEntrypoint([]string{"/usr/local/bin/zephyrite"})
+}
+
+// uploadGitHubRelease creates a GitHub Release for the given tag and uploads
+// every archive plus SHA256SUMS from ./dist/ as release assets. The token is
+// read from the host's GITHUB_TOKEN and handed to the container only as a
+// Dagger Secret, so it is never set as a plaintext container env var or
+// shown in logs.
+func uploadGitHubRelease(ctx context.Context, client *dagger.Client, tag string) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("GITHUB_TOKEN must be set to publish a GitHub Release")
+	}
+	secret := client.SetSecret("github_token", token)
+
+	distDir := client.Host().Directory("./dist")
+
+	// RELEASE_TAG is passed as an env var, never concatenated into the
+	// script, so a tag containing quotes/backticks/`$()` can't break the
+	// JSON payload or inject commands into the shell.
+	uploadScript := `set -e
+payload=$(jq -n --arg tag "$RELEASE_TAG" '{tag_name: $tag, name: $tag}')
+release_id=$(curl -sf -X POST \
+  -H "Authorization: Bearer $GITHUB_TOKEN" \
+  -H "Accept: application/vnd.github+json" \
+  https://api.github.com/repos/minikin/zephyrite/releases \
+  -d "$payload" | jq -r '.id')
+for asset in /dist/*; do
+  name=$(basename "$asset")
+  curl -sf -X POST \
+    -H "Authorization: Bearer $GITHUB_TOKEN" \
+    -H "Content-Type: application/octet-stream" \
+    "https://uploads.github.com/repos/minikin/zephyrite/releases/${release_id}/assets?name=${name}" \
+    --data-binary "@${asset}"
+done`
+
+	_, err := client.Container().
+		From("alpine:3.19").
+		WithExec([]string{"apk", "add", "--no-cache", "curl", "jq"}).
+		WithDirectory("/dist", distDir).
+		WithSecretVariable("GITHUB_TOKEN", secret).
+		WithEnvVariable("RELEASE_TAG", tag).
+		WithExec([]string{"sh", "-c", uploadScript}).
+		Sync(ctx)
+
+	return err
+}
+
+// publish assembles multi-arch distroless images, pushes them as a single
+// manifest-listed tag (plus :latest), and uploads the dist archives to a
+// matching GitHub Release. Each image's binary is cross-compiled here by
+// buildPlatformImage; the archives under ./dist/ are only uploaded, not used
+// to build the images.
+// --dry-run builds the per-platform images but applies no tag and skips
+// both the push and the GitHub Release upload.
+func publish(ctx context.Context, client *dagger.Client, opts publishOptions) error {
+	fmt.Printf("📦 Publishing Zephyrite %s to %s\n", opts.tag, opts.registry)
+
+	client = client.Pipeline("Zephyrite Publish")
+
+	sourceDir, err := getProjectSourceDirectory(client)
+	if err != nil {
+		return fmt.Errorf("failed to get project source directory: %w", err)
+	}
+
+	variants := make([]*dagger.Container, 0, len(publishLinuxTargets))
+	for target, platform := range publishLinuxTargets {
+		fmt.Printf("🔨 Building image for %s (%s)...\n", target, platform)
+		variants = append(variants, buildPlatformImage(client, sourceDir, target, platform))
+	}
+
+	if opts.dryRun {
+		// Dagger evaluates containers lazily, so without an explicit Sync here
+		// the variants built above would never actually execute.
+		for i, variant := range variants {
+			if _, err := variant.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to build image variant %d: %w", i, err)
+			}
+		}
+		fmt.Println("🧪 --dry-run set: built images without tagging, pushing or uploading release assets")
+		return nil
+	}
+
+	for _, ref := range []string{fmt.Sprintf("%s:%s", opts.registry, opts.tag), fmt.Sprintf("%s:latest", opts.registry)} {
+		fmt.Printf("⬆️  Pushing %s...\n", ref)
+		if _, err := client.Container().Publish(ctx, ref, dagger.ContainerPublishOpts{PlatformVariants: variants}); err != nil {
+			return fmt.Errorf("failed to publish %s: %w", ref, err)
+		}
+	}
+
+	fmt.Println("⬆️  Uploading GitHub Release assets...")
+	if err := uploadGitHubRelease(ctx, client, opts.tag); err != nil {
+		return fmt.Errorf("failed to upload GitHub Release: %w", err)
+	}
+
+	fmt.Println("✅ Published image and GitHub Release successfully!")
+	return nil
+}
+
+// devOptions configures the dev/shell subcommand.
+type devOptions struct {
+	target string
+	cmd    string
+}
+
+// parseDevFlags parses the flags accepted by the dev/shell subcommand.
+func parseDevFlags(args []string) (devOptions, error) {
+	fs := flag.NewFlagSet("dev", flag.ContinueOnError)
+	target := fs.String("target", "", "Rust target triple to pre-add via rustup before opening the shell")
+	cmd := fs.String("cmd", "", "run this shell command non-interactively instead of opening a terminal")
+
+	if err := fs.Parse(args); err != nil {
+		return devOptions{}, err
+	}
+
+	return devOptions{
+		target: *target,
+		cmd:    *cmd,
+	}, nil
+}
+
+// dev drops the caller into a container preloaded with the same toolchain
+// and cargo registry/git and sccache caches as test-local (its target
+// directory cache is its own), so contributors get a byte-identical build
+// environment to CI without installing Rust locally.
+func dev(ctx context.Context, client *dagger.Client, opts devOptions) error {
+	client = client.Pipeline("Zephyrite Dev")
 
 	sourceDir, err := getProjectSourceDirectory(client)
 	if err != nil {
@@ -127,20 +782,52 @@ func release(ctx context.Context, client *dagger.Client) error {
 	container := client.Container().
 		From("rust:1.85").
 		WithWorkdir("/workspace").
-		WithDirectory("/workspace", sourceDir).
-		WithExec([]string{"rustup", "component", "add", "rustfmt", "clippy"})
+		WithMountedDirectory("/workspace", sourceDir).
+		WithExec([]string{"rustup", "component", "add", "rustfmt", "clippy"}).
+		WithExec([]string{"cargo", "install", "cargo-nextest", "--locked"}).
+		WithExec([]string{"cargo", "install", "cargo-watch", "--locked"})
 
-	fmt.Println("🔨 Building release binary...")
-	container = container.WithExec([]string{"cargo", "build", "--release"})
+	container = withToolchainCaches(client, container, "dev")
 
-	binary := container.File("/workspace/target/release/zephyrite")
+	if opts.target != "" {
+		fmt.Printf("🎯 Adding target %s...\n", opts.target)
+		container = container.WithExec([]string{"rustup", "target", "add", opts.target})
+	}
 
-	_, err = binary.Export(ctx, "./target/release/zephyrite")
+	// Dagger has no true read-write bind mount back to the host, so sync
+	// anything the contributor's command or session edited back out once it
+	// finishes rather than silently discarding it. Build outputs under
+	// /workspace/target stay in the target cache volume and are not synced.
+	wd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to export binary: %w", err)
+		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	fmt.Println("✅ Release binary built successfully!")
-	fmt.Println("📦 Binary available at: ./target/release/zephyrite")
+	if opts.cmd != "" {
+		fmt.Printf("🏃 Running %q...\n", opts.cmd)
+		ran := container.WithExec([]string{"sh", "-c", opts.cmd})
+		out, err := ran.Stdout(ctx)
+		if err != nil {
+			return fmt.Errorf("dev command failed: %w", err)
+		}
+		fmt.Println(out)
+
+		fmt.Println("💾 Syncing workspace back to host...")
+		if _, err := ran.Directory("/workspace").Export(ctx, filepath.Dir(wd)); err != nil {
+			return fmt.Errorf("failed to export workspace after dev command: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Println("🐚 Opening interactive dev shell...")
+	session, err := container.Terminal().Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("💾 Syncing workspace back to host...")
+	if _, err := session.Directory("/workspace").Export(ctx, filepath.Dir(wd)); err != nil {
+		return fmt.Errorf("failed to export workspace after dev session: %w", err)
+	}
 	return nil
 }